@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkHeaderRE = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@(.*)$`)
+
+// trimHunkContext rewrites each hunk in patch so that at most n unchanged
+// ("context") lines remain at the start and end of the hunk, adjusting the
+// "@@ -a,b +c,d @@" header to match. It only trims the head and tail of a
+// hunk, not context buried between two changes further apart than n lines;
+// reducing that would require re-splitting hunks, which the diff API does
+// not give us enough information to do safely.
+func trimHunkContext(patch string, n int) string {
+	if n < 0 || patch == "" {
+		return patch
+	}
+
+	lines := strings.Split(patch, "\n")
+	trailingNewline := false
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		trailingNewline = true
+		lines = lines[:len(lines)-1]
+	}
+
+	var out []string
+	var header string
+	var hunk []string
+
+	flush := func() {
+		if header == "" {
+			return
+		}
+		out = append(out, trimHunk(header, hunk, n)...)
+		header = ""
+		hunk = nil
+	}
+
+	for _, line := range lines {
+		if hunkHeaderRE.MatchString(line) {
+			flush()
+			header = line
+			continue
+		}
+		if header != "" {
+			hunk = append(hunk, line)
+		} else {
+			out = append(out, line)
+		}
+	}
+	flush()
+
+	result := strings.Join(out, "\n")
+	if trailingNewline {
+		result += "\n"
+	}
+	return result
+}
+
+func trimHunk(header string, lines []string, n int) []string {
+	m := hunkHeaderRE.FindStringSubmatch(header)
+	if m == nil {
+		return append([]string{header}, lines...)
+	}
+
+	oldStart, _ := strconv.Atoi(m[1])
+	newStart, _ := strconv.Atoi(m[3])
+
+	lead := 0
+	for lead < len(lines) && strings.HasPrefix(lines[lead], " ") {
+		lead++
+	}
+	trail := 0
+	for trail < len(lines)-lead && strings.HasPrefix(lines[len(lines)-1-trail], " ") {
+		trail++
+	}
+
+	dropLead := 0
+	if lead > n {
+		dropLead = lead - n
+	}
+	dropTrail := 0
+	if trail > n {
+		dropTrail = trail - n
+	}
+
+	trimmed := lines[dropLead : len(lines)-dropTrail]
+	oldStart += dropLead
+	newStart += dropLead
+	oldCount := countPrefix(trimmed, " ", "-")
+	newCount := countPrefix(trimmed, " ", "+")
+
+	newHeader := fmt.Sprintf("@@ -%d,%d +%d,%d @@%s", oldStart, oldCount, newStart, newCount, m[5])
+	return append([]string{newHeader}, trimmed...)
+}
+
+func countPrefix(lines []string, prefixes ...string) int {
+	count := 0
+	for _, l := range lines {
+		for _, p := range prefixes {
+			if strings.HasPrefix(l, p) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
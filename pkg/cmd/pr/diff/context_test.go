@@ -0,0 +1,48 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimHunkContext(t *testing.T) {
+	patch := strings.Join([]string{
+		`diff --git a/main.go b/main.go`,
+		`index 83db48f..bf269f4 100644`,
+		`--- a/main.go`,
+		`+++ b/main.go`,
+		`@@ -1,7 +1,7 @@`,
+		` package main`,
+		` `,
+		` import "fmt"`,
+		` `,
+		`-func old() {}`,
+		`+func new() {}`,
+		` `,
+		` func main() {}`,
+		``,
+	}, "\n")
+
+	got := trimHunkContext(patch, 1)
+
+	want := strings.Join([]string{
+		`diff --git a/main.go b/main.go`,
+		`index 83db48f..bf269f4 100644`,
+		`--- a/main.go`,
+		`+++ b/main.go`,
+		`@@ -4,3 +4,3 @@`,
+		` `,
+		`-func old() {}`,
+		`+func new() {}`,
+		` `,
+		``,
+	}, "\n")
+	assert.Equal(t, want, got)
+}
+
+func TestTrimHunkContext_NegativeIsNoop(t *testing.T) {
+	patch := "diff --git a/x b/x\n@@ -1,3 +1,3 @@\n foo\n-bar\n+baz\n"
+	assert.Equal(t, patch, trimHunkContext(patch, -1))
+}
@@ -1,21 +1,18 @@
 package diff
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/cli/cli/api"
 	"github.com/cli/cli/context"
+	"github.com/cli/cli/internal/config"
 	"github.com/cli/cli/internal/ghrepo"
 	"github.com/cli/cli/pkg/cmd/pr/shared"
 	"github.com/cli/cli/pkg/cmdutil"
 	"github.com/cli/cli/pkg/iostreams"
-	"github.com/google/shlex"
 	"github.com/spf13/cobra"
 )
 
@@ -25,9 +22,24 @@ type DiffOptions struct {
 	BaseRepo   func() (ghrepo.Interface, error)
 	Remotes    func() (context.Remotes, error)
 	Branch     func() (string, error)
+	Config     func() (config.Config, error)
 
 	SelectorArg string
 	UseColor    string
+
+	Highlight      string
+	HighlightStyle string
+
+	NameOnly bool
+	Stat     bool
+	JSON     bool
+
+	Paths   []string
+	Reverse bool
+	Context int
+	NoPager bool
+
+	WordDiff string
 }
 
 func NewCmdDiff(f *cmdutil.Factory, runF func(*DiffOptions) error) *cobra.Command {
@@ -36,27 +48,67 @@ func NewCmdDiff(f *cmdutil.Factory, runF func(*DiffOptions) error) *cobra.Comman
 		HttpClient: f.HttpClient,
 		Remotes:    f.Remotes,
 		Branch:     f.Branch,
+		Config:     f.Config,
+		Context:    -1,
+		WordDiff:   "none",
 	}
 
 	cmd := &cobra.Command{
-		Use:   "diff [<number> | <url> | <branch>]",
+		Use:   "diff [<number> | <url> | <branch>] [-- <path>...]",
 		Short: "View changes in a pull request",
-		Args:  cobra.MaximumNArgs(1),
+		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// support `-R, --repo` override
 			opts.BaseRepo = f.BaseRepo
 
-			if len(args) > 0 {
-				opts.SelectorArg = args[0]
+			selectorArgs := args
+			if dashAt := cmd.ArgsLenAtDash(); dashAt > -1 {
+				selectorArgs = args[:dashAt]
+				opts.Paths = append(opts.Paths, args[dashAt:]...)
+			}
+			if len(selectorArgs) > 1 {
+				return &cmdutil.FlagError{Err: fmt.Errorf("expected at most 1 positional argument before `--`")}
+			}
+			if len(selectorArgs) == 1 {
+				opts.SelectorArg = selectorArgs[0]
 			}
 
 			if !validColorFlag(opts.UseColor) {
 				return &cmdutil.FlagError{Err: fmt.Errorf("did not understand color: %q. Expected one of always, never, or auto", opts.UseColor)}
 			}
+			if !validColorFlag(opts.Highlight) {
+				return &cmdutil.FlagError{Err: fmt.Errorf("did not understand highlight: %q. Expected one of always, never, or auto", opts.Highlight)}
+			}
+			if !validWordDiffFlag(opts.WordDiff) {
+				return &cmdutil.FlagError{Err: fmt.Errorf("did not understand word-diff: %q. Expected one of none, color, or plain", opts.WordDiff)}
+			}
+
+			modesSet := 0
+			for _, set := range []bool{opts.NameOnly, opts.Stat, opts.JSON} {
+				if set {
+					modesSet++
+				}
+			}
+			if modesSet > 1 {
+				return &cmdutil.FlagError{Err: fmt.Errorf("specify only one of --name-only, --stat, or --json")}
+			}
+			if modesSet > 0 && (cmd.Flags().Changed("color") || cmd.Flags().Changed("highlight")) {
+				return &cmdutil.FlagError{Err: fmt.Errorf("--color and --highlight are not supported with --name-only, --stat, or --json")}
+			}
 
 			if opts.UseColor == "auto" && !opts.IO.IsStdoutTTY() {
 				opts.UseColor = "never"
 			}
+			if opts.Highlight == "auto" {
+				if opts.UseColor == "never" || !opts.IO.IsStdoutTTY() {
+					opts.Highlight = "never"
+				} else {
+					opts.Highlight = "always"
+				}
+			}
+			if opts.Highlight == "always" && opts.UseColor == "never" {
+				return &cmdutil.FlagError{Err: fmt.Errorf("--highlight=always is incompatible with --color=never")}
+			}
 
 			if runF != nil {
 				return runF(opts)
@@ -66,6 +118,16 @@ func NewCmdDiff(f *cmdutil.Factory, runF func(*DiffOptions) error) *cobra.Comman
 	}
 
 	cmd.Flags().StringVar(&opts.UseColor, "color", "auto", "Use color in diff output: {always|never|auto}")
+	cmd.Flags().StringVar(&opts.Highlight, "highlight", "auto", "Syntax-highlight diff contents: {always|never|auto}")
+	cmd.Flags().StringVar(&opts.HighlightStyle, "highlight-style", "", "Chroma style used for syntax highlighting (default: monokai, or github on light terminals)")
+	cmd.Flags().BoolVar(&opts.NameOnly, "name-only", false, "Print only the names of changed files")
+	cmd.Flags().BoolVar(&opts.Stat, "stat", false, "Print a diffstat-style summary of changes")
+	cmd.Flags().BoolVar(&opts.JSON, "json", false, "Print a machine-readable JSON summary of changes")
+	cmd.Flags().StringArrayVarP(&opts.Paths, "path", "p", nil, "Filter the diff to files matching the given glob (can be used multiple times)")
+	cmd.Flags().BoolVar(&opts.Reverse, "reverse", false, "Swap the addition/removal colors, e.g. when reviewing a diff in the opposite direction")
+	cmd.Flags().IntVar(&opts.Context, "context", -1, "Limit the number of context lines kept at the start and end of each hunk")
+	cmd.Flags().BoolVar(&opts.NoPager, "no-pager", false, "Disable the pager for this diff, overriding any configured pager")
+	cmd.Flags().StringVar(&opts.WordDiff, "word-diff", "none", "Highlight word-level changes within modified lines: {none|color|plain}")
 
 	return cmd
 }
@@ -88,33 +150,60 @@ func diffRun(opts *DiffOptions) error {
 	}
 	defer diff.Close()
 
-	if opts.UseColor == "never" {
+	matcher, err := newPathMatcher(opts.Paths)
+	if err != nil {
+		return &cmdutil.FlagError{Err: fmt.Errorf("invalid --path pattern: %w", err)}
+	}
+
+	switch {
+	case opts.NameOnly:
+		return printNameOnly(diff, opts.IO.Out, matcher)
+	case opts.Stat:
+		return printStat(diff, opts.IO.Out, opts.IO.TerminalWidth(), matcher)
+	case opts.JSON:
+		return printJSON(diff, opts.IO.Out, matcher)
+	}
+
+	// --word-diff and --reverse are applied by renderDiff, so setting either
+	// routes the diff through it even when color is otherwise suppressed
+	// (e.g. piped output on a non-TTY stdout). renderDiff's output always
+	// carries ANSI color codes; --reverse only swaps the +/- colors, while
+	// --word-diff=plain also adds [-...-]/{+...+} markers to the text.
+	needsRendering := opts.WordDiff != "none" || opts.Reverse
+
+	if matcher != nil || opts.Context >= 0 {
+		return printFiltered(diff, opts.IO.Out, matcher, opts.Context, opts.UseColor != "never" || needsRendering, renderOpts{
+			highlight:      opts.Highlight == "always",
+			highlightStyle: resolveHighlightStyle(opts.HighlightStyle),
+			reverse:        opts.Reverse,
+			wordDiff:       opts.WordDiff,
+		})
+	}
+
+	if opts.UseColor == "never" && !needsRendering {
 		_, err = io.Copy(opts.IO.Out, diff)
 		return err
 	}
 
-	if opts.IO.IsStdoutTTY() {
-		if pager := os.Getenv("PAGER"); pager != "" {
-			return runPager(pager, diff, opts.IO.Out)
+	if !opts.NoPager && opts.IO.IsStdoutTTY() {
+		var cfg configGetter
+		if opts.Config != nil {
+			if c, cfgErr := opts.Config(); cfgErr == nil {
+				cfg = c
+			}
 		}
-	}
-
-	diffLines := bufio.NewScanner(diff)
-	for diffLines.Scan() {
-		diffLine := diffLines.Text()
-		switch {
-		case isHeaderLine(diffLine):
-			fmt.Fprintf(opts.IO.Out, "\x1b[1;38m%s\x1b[m\n", diffLine)
-		case isAdditionLine(diffLine):
-			fmt.Fprintf(opts.IO.Out, "\x1b[32m%s\x1b[m\n", diffLine)
-		case isRemovalLine(diffLine):
-			fmt.Fprintf(opts.IO.Out, "\x1b[31m%s\x1b[m\n", diffLine)
-		default:
-			fmt.Fprintln(opts.IO.Out, diffLine)
+		if pager := resolvePager(cfg); pager != "" {
+			return runPager(pager, diff, opts.IO.Out)
 		}
 	}
 
-	if err := diffLines.Err(); err != nil {
+	err = renderDiff(diff, opts.IO.Out, renderOpts{
+		highlight:      opts.Highlight == "always",
+		highlightStyle: resolveHighlightStyle(opts.HighlightStyle),
+		reverse:        opts.Reverse,
+		wordDiff:       opts.WordDiff,
+	})
+	if err != nil {
 		return fmt.Errorf("error reading pull request diff: %w", err)
 	}
 
@@ -144,13 +233,6 @@ func validColorFlag(c string) bool {
 	return c == "auto" || c == "always" || c == "never"
 }
 
-var runPager = func(pager string, diff io.Reader, out io.Writer) error {
-	args, err := shlex.Split(pager)
-	if err != nil {
-		return err
-	}
-	pagerCmd := exec.Command(args[0], args[1:]...)
-	pagerCmd.Stdin = diff
-	pagerCmd.Stdout = out
-	return pagerCmd.Run()
+func validWordDiffFlag(c string) bool {
+	return c == "none" || c == "color" || c == "plain"
 }
@@ -0,0 +1,181 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cli/cli/pkg/cmd/pr/shared/diffparse"
+)
+
+// matches reports whether f passes the given path matcher, treating a nil
+// matcher as "match everything".
+func matches(m *pathMatcher, f diffparse.FileDiff) bool {
+	return m == nil || m.Match(f.Path, f.PreviousPath)
+}
+
+var errNoFilesMatch = fmt.Errorf("no files match the given --path pattern(s)")
+
+// printNameOnly prints one changed path per line, in diff order, honoring
+// renames by printing the file's current path.
+func printNameOnly(diff io.Reader, w io.Writer, matcher *pathMatcher) error {
+	matched := false
+	err := diffparse.Each(diff, false, func(f diffparse.FileDiff) error {
+		if !matches(matcher, f) {
+			return nil
+		}
+		matched = true
+		_, err := fmt.Fprintln(w, f.Path)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if matcher != nil && !matched {
+		return errNoFilesMatch
+	}
+	return nil
+}
+
+// printJSON streams `{"files":[...]}` to w, encoding each file as it is
+// parsed out of the diff rather than collecting the whole document first.
+func printJSON(diff io.Reader, w io.Writer, matcher *pathMatcher) error {
+	// The opening bracket is deferred until the first matched file (and
+	// written up front if there turns out to be none) so that a --path miss
+	// returns errNoFilesMatch without leaving any partial JSON on w.
+	opened, matched := false, false
+	err := diffparse.Each(diff, true, func(f diffparse.FileDiff) error {
+		if !matches(matcher, f) {
+			return nil
+		}
+		matched = true
+		if !opened {
+			if _, err := io.WriteString(w, `{"files":[`); err != nil {
+				return err
+			}
+			opened = true
+		} else {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		b, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if matcher != nil && !matched {
+		return errNoFilesMatch
+	}
+	if !opened {
+		if _, err := io.WriteString(w, `{"files":[`); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "]}\n")
+	return err
+}
+
+const maxStatBarWidth = 60
+
+// printStat prints a diffstat-style summary: one line per file with a bar
+// scaled to the terminal width, followed by a totals line.
+func printStat(diff io.Reader, w io.Writer, termWidth int, matcher *pathMatcher) error {
+	var files []diffparse.FileDiff
+	err := diffparse.Each(diff, false, func(f diffparse.FileDiff) error {
+		if matches(matcher, f) {
+			files = append(files, f)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if matcher != nil && len(files) == 0 {
+		return errNoFilesMatch
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	maxPathLen, maxChanges, totalAdd, totalDel := 0, 0, 0, 0
+	for _, f := range files {
+		if l := len(f.Path); l > maxPathLen {
+			maxPathLen = l
+		}
+		if c := f.Additions + f.Deletions; c > maxChanges {
+			maxChanges = c
+		}
+		totalAdd += f.Additions
+		totalDel += f.Deletions
+	}
+
+	numWidth := len(fmt.Sprintf("%d", maxChanges))
+	barWidth := termWidth - maxPathLen - numWidth - len(" |  ")
+	if barWidth > maxStatBarWidth {
+		barWidth = maxStatBarWidth
+	}
+	if barWidth < 1 {
+		barWidth = 1
+	}
+
+	for _, f := range files {
+		changes := f.Additions + f.Deletions
+		plus, minus := 0, 0
+		if maxChanges > 0 {
+			scale := float64(barWidth) / float64(maxChanges)
+			plus = int(float64(f.Additions) * scale)
+			minus = int(float64(f.Deletions) * scale)
+			if changes > 0 && plus+minus == 0 {
+				if f.Additions >= f.Deletions {
+					plus = 1
+				} else {
+					minus = 1
+				}
+			}
+		}
+		bar := strings.Repeat("+", plus) + strings.Repeat("-", minus)
+		if _, err := fmt.Fprintf(w, "%-*s | %*d %s\n", maxPathLen, f.Path, numWidth, changes, bar); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, " %d file(s) changed, %d insertion(+), %d deletion(-)\n", len(files), totalAdd, totalDel)
+	return err
+}
+
+// printFiltered renders only the files that match matcher (or all files if
+// matcher is nil), applying context trimming and colorization per file as
+// each one is parsed out of the diff.
+func printFiltered(diff io.Reader, w io.Writer, matcher *pathMatcher, contextLines int, useColor bool, opts renderOpts) error {
+	matched := false
+	err := diffparse.Each(diff, true, func(f diffparse.FileDiff) error {
+		if !matches(matcher, f) {
+			return nil
+		}
+		matched = true
+
+		patch := f.Patch
+		if contextLines >= 0 {
+			patch = trimHunkContext(patch, contextLines)
+		}
+		if !useColor {
+			_, err := io.WriteString(w, patch)
+			return err
+		}
+		return renderDiff(strings.NewReader(patch), w, opts)
+	})
+	if err != nil {
+		return err
+	}
+	if matcher != nil && !matched {
+		return errNoFilesMatch
+	}
+	return nil
+}
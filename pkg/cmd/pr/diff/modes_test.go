@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const modesFixtureDiff = `diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-func old() {}
++func new() {}
+diff --git a/old_name.go b/new_name.go
+similarity index 90%
+rename from old_name.go
+rename to new_name.go
+index 1234567..89abcde 100644
+--- a/old_name.go
++++ b/new_name.go
+@@ -1,2 +1,3 @@
+ package main
++// added a comment
+`
+
+func TestPrintNameOnly(t *testing.T) {
+	var buf bytes.Buffer
+	err := printNameOnly(strings.NewReader(modesFixtureDiff), &buf, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "main.go\nnew_name.go\n", buf.String())
+}
+
+func TestPrintJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := printJSON(strings.NewReader(modesFixtureDiff), &buf, nil)
+	assert.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, `"path":"main.go"`)
+	assert.Contains(t, out, `"additions":1`)
+	assert.Contains(t, out, `"previous_path":"old_name.go"`)
+	assert.Contains(t, out, `"status":"renamed"`)
+	assert.True(t, strings.HasPrefix(out, `{"files":[`))
+}
+
+func TestPrintJSON_NoMatches(t *testing.T) {
+	var buf bytes.Buffer
+	matcher, err := newPathMatcher([]string{"nope/*.rb"})
+	assert.NoError(t, err)
+	err = printJSON(strings.NewReader(modesFixtureDiff), &buf, matcher)
+	assert.Equal(t, errNoFilesMatch, err)
+	assert.Empty(t, buf.String())
+}
+
+func TestPrintStat(t *testing.T) {
+	var buf bytes.Buffer
+	err := printStat(strings.NewReader(modesFixtureDiff), &buf, 80, nil)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "main.go")
+	assert.Contains(t, lines[1], "new_name.go")
+	assert.Contains(t, lines[2], "2 file(s) changed")
+}
+
+func TestPrintNameOnly_Filtered(t *testing.T) {
+	var buf bytes.Buffer
+	matcher, err := newPathMatcher([]string{"new_name.go"})
+	assert.NoError(t, err)
+	err = printNameOnly(strings.NewReader(modesFixtureDiff), &buf, matcher)
+	assert.NoError(t, err)
+	assert.Equal(t, "new_name.go\n", buf.String())
+}
+
+func TestPrintFiltered_RetainsDiffGitHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	matcher, err := newPathMatcher([]string{"new_name.go"})
+	assert.NoError(t, err)
+	err = printFiltered(strings.NewReader(modesFixtureDiff), &buf, matcher, -1, false, renderOpts{})
+	assert.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "diff --git a/old_name.go b/new_name.go")
+	assert.NotContains(t, out, "diff --git a/main.go b/main.go")
+}
+
+const binaryFixtureDiff = `diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-func old() {}
++func new() {}
+diff --git a/image.png b/image.png
+new file mode 100644
+index 0000000..abcdef0
+Binary files /dev/null and b/image.png differ
+`
+
+func TestPrintFiltered_BinaryFile(t *testing.T) {
+	var buf bytes.Buffer
+	matcher, err := newPathMatcher([]string{"image.png"})
+	assert.NoError(t, err)
+	err = printFiltered(strings.NewReader(binaryFixtureDiff), &buf, matcher, -1, false, renderOpts{})
+	assert.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "diff --git a/image.png b/image.png")
+	assert.Contains(t, out, "Binary files /dev/null and b/image.png differ")
+}
+
+func TestPrintFiltered_BinaryFile_WithContext(t *testing.T) {
+	var buf bytes.Buffer
+	err := printFiltered(strings.NewReader(binaryFixtureDiff), &buf, nil, 3, false, renderOpts{})
+	assert.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "Binary files /dev/null and b/image.png differ")
+}
+
+func TestPrintNameOnly_NoMatches(t *testing.T) {
+	var buf bytes.Buffer
+	matcher, err := newPathMatcher([]string{"nope/*.rb"})
+	assert.NoError(t, err)
+	err = printNameOnly(strings.NewReader(modesFixtureDiff), &buf, matcher)
+	assert.Equal(t, errNoFilesMatch, err)
+}
@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeConfig map[string]string
+
+func (c fakeConfig) Get(hostname, key string) (string, error) {
+	if v, ok := c[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("no such key: %s", key)
+}
+
+func TestResolvePager(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		cfg  configGetter
+		want string
+	}{
+		{
+			name: "GH_PAGER takes precedence over everything",
+			env:  map[string]string{"GH_PAGER": "ghpager", "PAGER": "pager"},
+			cfg:  fakeConfig{"pager": "configpager", "pr.diff.pager": "diffpager"},
+			want: "ghpager",
+		},
+		{
+			name: "pr.diff.pager overrides the general pager config",
+			cfg:  fakeConfig{"pager": "configpager", "pr.diff.pager": "diffpager"},
+			want: "diffpager",
+		},
+		{
+			name: "falls back to the general pager config",
+			cfg:  fakeConfig{"pager": "configpager"},
+			want: "configpager",
+		},
+		{
+			name: "falls back to $PAGER when nothing is configured",
+			env:  map[string]string{"PAGER": "pager"},
+			cfg:  fakeConfig{},
+			want: "pager",
+		},
+		{
+			name: "empty when nothing is set",
+			cfg:  fakeConfig{},
+			want: "",
+		},
+		{
+			name: "nil config is tolerated",
+			env:  map[string]string{"PAGER": "pager"},
+			cfg:  nil,
+			want: "pager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"GH_PAGER", "PAGER"} {
+				os.Unsetenv(k)
+			}
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			assert.Equal(t, tt.want, resolvePager(tt.cfg))
+		})
+	}
+}
+
+func TestPagerEnvDefaults(t *testing.T) {
+	os.Unsetenv("LESS")
+	os.Unsetenv("LV")
+	env := pagerEnvDefaults()
+	assert.Contains(t, env, "LESS=FRX")
+	assert.Contains(t, env, "LV=-c")
+
+	os.Setenv("LESS", "custom")
+	defer os.Unsetenv("LESS")
+	env = pagerEnvDefaults()
+	assert.NotContains(t, env, "LESS=FRX")
+	assert.Contains(t, env, "LV=-c")
+}
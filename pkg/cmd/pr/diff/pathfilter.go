@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pathMatcher matches file paths against a set of glob patterns. In
+// addition to `path.Match`'s `*` and `?`, it supports `**` to match any
+// number of path segments, mirroring `git diff -- <pathspec>` closely enough
+// for everyday use.
+type pathMatcher struct {
+	patterns []*regexp.Regexp
+}
+
+func newPathMatcher(globs []string) (*pathMatcher, error) {
+	if len(globs) == 0 {
+		return nil, nil
+	}
+	m := &pathMatcher{}
+	for _, g := range globs {
+		re, err := compileGlob(g)
+		if err != nil {
+			return nil, err
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Match reports whether any of path or previousPath (the latter may be
+// empty) matches one of the matcher's patterns.
+func (m *pathMatcher) Match(path, previousPath string) bool {
+	if m == nil {
+		return true
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(path) || (previousPath != "" && re.MatchString(previousPath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileGlob translates a glob into an anchored regexp. A pattern with no
+// wildcard characters is treated as a `git diff -- <pathspec>` directory
+// prefix as well as a literal path, so "pkg/cmd" matches both the file
+// "pkg/cmd" and anything under the "pkg/cmd/" subtree.
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimSuffix(glob, "/")
+	literal := !strings.ContainsAny(glob, "*?")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); {
+		switch c := glob[i]; {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i += 2
+			if i < len(glob) && glob[i] == '/' {
+				// "**/" spans zero or more whole segments.
+				sb.WriteString("(.*/)?")
+				i++
+			} else {
+				sb.WriteString(".*")
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+			i++
+		case c == '?':
+			sb.WriteString("[^/]")
+			i++
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+			i++
+		}
+	}
+	if literal {
+		sb.WriteString("(/.*)?")
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
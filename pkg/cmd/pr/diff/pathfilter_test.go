@@ -0,0 +1,41 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathMatcher(t *testing.T) {
+	tests := []struct {
+		name         string
+		globs        []string
+		path         string
+		previousPath string
+		want         bool
+	}{
+		{"nil matcher matches everything", nil, "pkg/cmd/pr/diff/diff.go", "", true},
+		{"exact match", []string{"diff.go"}, "diff.go", "", true},
+		{"single star stays within a segment", []string{"pkg/*/diff.go"}, "pkg/cmd/diff.go", "", true},
+		{"single star does not cross segments", []string{"pkg/*/diff.go"}, "pkg/cmd/pr/diff.go", "", false},
+		{"double star crosses segments", []string{"pkg/**/diff.go"}, "pkg/cmd/pr/diff/diff.go", "", true},
+		{"matches previous path for renames", []string{"old_name.go"}, "new_name.go", "old_name.go", true},
+		{"no match", []string{"*.rb"}, "main.go", "", false},
+		{"literal pattern matches files under the directory", []string{"pkg/cmd"}, "pkg/cmd/pr/diff/diff.go", "", true},
+		{"literal pattern with trailing slash matches files under the directory", []string{"pkg/cmd/"}, "pkg/cmd/pr/diff/diff.go", "", true},
+		{"literal pattern does not match a sibling directory", []string{"pkg/cmd"}, "pkg/cmdutil/factory.go", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newPathMatcher(tt.globs)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, m.Match(tt.path, tt.previousPath))
+		})
+	}
+}
+
+func TestNewPathMatcher_Empty(t *testing.T) {
+	m, err := newPathMatcher(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+}
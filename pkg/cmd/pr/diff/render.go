@@ -0,0 +1,172 @@
+package diff
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/formatters"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+)
+
+// renderOpts configures renderDiff. highlight controls whether Chroma
+// tokenizes added/removed content; when it is false renderDiff falls back to
+// the plain "+/-" colorizer. wordDiff is one of "none", "color", or "plain";
+// when not "none" it takes priority over highlight for paired removed/added
+// lines, since Chroma tokens and word-diff runs would otherwise conflict.
+type renderOpts struct {
+	highlight      bool
+	highlightStyle string
+	reverse        bool
+	wordDiff       string
+}
+
+var diffGitLineRE = regexp.MustCompile(`^diff --git a/(.+) b/(.+)$`)
+
+// renderDiff copies a unified diff from r to w, colorizing "+"/"-" lines and,
+// when opts.highlight is set, syntax-highlighting their content according to
+// the language of the file the current hunk belongs to. It is the single
+// code path shared by the plain colorizer and the Chroma-backed highlighter
+// so that both treat headers, hunks, and EOF markers identically.
+func renderDiff(r io.Reader, w io.Writer, opts renderOpts) error {
+	style := styles.Get(opts.highlightStyle)
+	if style == nil {
+		style = styles.Fallback
+	}
+	formatter := formatters.TTY16m
+
+	additionColor, removalColor := "\x1b[32m", "\x1b[31m"
+	if opts.reverse {
+		additionColor, removalColor = removalColor, additionColor
+	}
+
+	wordDiff := opts.wordDiff != "" && opts.wordDiff != "none"
+
+	var lexer chroma.Lexer
+	var pendingRemovals, pendingAdditions []string
+
+	flushPending := func() {
+		if len(pendingRemovals) == 0 && len(pendingAdditions) == 0 {
+			return
+		}
+		if wordDiff && len(pendingRemovals) == len(pendingAdditions) {
+			for i := range pendingRemovals {
+				renderWordDiffPair(w, pendingRemovals[i], pendingAdditions[i], opts.wordDiff, removalColor, additionColor)
+			}
+		} else {
+			// Unequal removal/addition counts can't be paired line-for-line,
+			// so fall back to whole-line rendering in the original order.
+			for _, l := range pendingRemovals {
+				writeDiffLine(w, "-", removalColor, l, lexer, style, formatter, opts.highlight)
+			}
+			for _, l := range pendingAdditions {
+				writeDiffLine(w, "+", additionColor, l, lexer, style, formatter, opts.highlight)
+			}
+		}
+		pendingRemovals = nil
+		pendingAdditions = nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushPending()
+			if opts.highlight {
+				lexer = lexerForDiffGitLine(line)
+			}
+			writePlain(w, "\x1b[1;38m", line)
+		case isHeaderLine(line):
+			flushPending()
+			writePlain(w, "\x1b[1;38m", line)
+		case isAdditionLine(line):
+			pendingAdditions = append(pendingAdditions, line[1:])
+		case isRemovalLine(line):
+			if len(pendingAdditions) > 0 {
+				flushPending()
+			}
+			pendingRemovals = append(pendingRemovals, line[1:])
+		default:
+			flushPending()
+			io.WriteString(w, line)
+			io.WriteString(w, "\n")
+		}
+	}
+	flushPending()
+	return scanner.Err()
+}
+
+func writePlain(w io.Writer, color, line string) {
+	io.WriteString(w, color)
+	io.WriteString(w, line)
+	io.WriteString(w, "\x1b[m\n")
+}
+
+func writeDiffLine(w io.Writer, sign, color, content string, lexer chroma.Lexer, style *chroma.Style, formatter chroma.Formatter, highlight bool) {
+	io.WriteString(w, color)
+	io.WriteString(w, sign)
+	if highlight && lexer != nil {
+		if iter, err := lexer.Tokenise(nil, content); err == nil {
+			if err := formatter.Format(w, style, iter); err == nil {
+				io.WriteString(w, "\x1b[m\n")
+				return
+			}
+		}
+	}
+	io.WriteString(w, content)
+	io.WriteString(w, "\x1b[m\n")
+}
+
+// lexerForDiffGitLine picks a Chroma lexer for the file touched by a
+// "diff --git a/... b/..." header, preferring the new path so renames pick up
+// the destination's extension. It returns nil for unrecognized or binary
+// files, in which case callers fall back to plain coloring.
+func lexerForDiffGitLine(line string) chroma.Lexer {
+	m := diffGitLineRE.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	l := lexers.Match(m[2])
+	if l == nil {
+		l = lexers.Match(m[1])
+	}
+	if l == nil {
+		return nil
+	}
+	return chroma.Coalesce(l)
+}
+
+// resolveHighlightStyle returns the user-requested Chroma style, or a default
+// based on a best-effort guess at whether the terminal is light or dark.
+func resolveHighlightStyle(style string) string {
+	if style != "" {
+		return style
+	}
+	if isLightTerminal() {
+		return "github"
+	}
+	return "monokai"
+}
+
+// isLightTerminal makes a best-effort guess from the COLORFGBG environment
+// variable, which many terminal emulators set to "foreground;background" and
+// which shells like vim already rely on for the same purpose.
+func isLightTerminal() bool {
+	colorFGBG := os.Getenv("COLORFGBG")
+	parts := strings.Split(colorFGBG, ";")
+	if len(parts) < 2 {
+		return false
+	}
+	switch parts[len(parts)-1] {
+	case "7", "15":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexerForDiffGitLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantLang string // "" means no lexer found
+	}{
+		{"go file", "diff --git a/main.go b/main.go", "Go"},
+		{"python file", "diff --git a/script.py b/script.py", "Python"},
+		{"javascript file", "diff --git a/app.js b/app.js", "JavaScript"},
+		{"unrecognized extension", "diff --git a/image.png b/image.png", ""},
+		{"not a diff --git line", "index 83db48f..bf269f4 100644", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lexer := lexerForDiffGitLine(tt.line)
+			if tt.wantLang == "" {
+				assert.Nil(t, lexer)
+				return
+			}
+			if assert.NotNil(t, lexer) {
+				assert.Equal(t, tt.wantLang, lexer.Config().Name)
+			}
+		})
+	}
+}
+
+func TestRenderDiff_Highlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		fixture   string
+		golden    string
+		highlight bool
+	}{
+		{"go", "testdata/highlight_go.diff", "testdata/highlight_go.golden", true},
+		{"python", "testdata/highlight_py.diff", "testdata/highlight_py.golden", true},
+		{"javascript", "testdata/highlight_js.diff", "testdata/highlight_js.golden", true},
+		{"binary falls back to plain coloring", "testdata/highlight_binary.diff", "testdata/highlight_binary.golden", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := ioutil.ReadFile(tt.fixture)
+			assert.NoError(t, err)
+
+			var plain bytes.Buffer
+			err = renderDiff(bytes.NewReader(raw), &plain, renderOpts{highlight: false})
+			assert.NoError(t, err)
+
+			var highlighted bytes.Buffer
+			err = renderDiff(bytes.NewReader(raw), &highlighted, renderOpts{highlight: true, highlightStyle: "monokai"})
+			assert.NoError(t, err)
+
+			golden, err := ioutil.ReadFile(tt.golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(golden), highlighted.String(), "rendered output drifted from %s; if this is an intentional change, regenerate the golden file", tt.golden)
+
+			if tt.name == "binary falls back to plain coloring" {
+				assert.Equal(t, plain.String(), highlighted.String())
+			} else {
+				// The output must still contain the diff's own colored "+"/"-"
+				// markers so the highlighter never loses the underlying diff markup.
+				assert.Contains(t, highlighted.String(), "\x1b[32m+")
+				assert.Contains(t, highlighted.String(), "\x1b[31m-")
+				assert.NotEqual(t, plain.String(), highlighted.String(), "expected syntax highlighting to change the rendered output")
+				assert.True(t, strings.Count(highlighted.String(), "\x1b[") > strings.Count(plain.String(), "\x1b["))
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// wordDiffSimilarityThreshold is the minimum Levenshtein ratio a
+// removed/added line pair must meet to be rendered as a word-level diff;
+// below it the pair is treated as unrelated and rendered as whole lines, to
+// avoid highlighting noise between lines that don't actually correspond.
+const wordDiffSimilarityThreshold = 0.5
+
+var wordTokenRE = regexp.MustCompile(`\w+|\W+`)
+
+// tokenizeWords splits s into words and the non-word runs between them
+// (whitespace, punctuation), so that re-joining every token reproduces s.
+func tokenizeWords(s string) []string {
+	return wordTokenRE.FindAllString(s, -1)
+}
+
+// diffOp is one run of a token-level edit script: "equal" tokens appear in
+// both sides, "removed" only in the old line, "added" only in the new line.
+type diffOp struct {
+	kind string
+	text string
+}
+
+// diffTokens aligns two token slices with a classic LCS table and returns
+// the resulting edit script, merging adjacent runs of the same kind.
+func diffTokens(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	push := func(kind, text string) {
+		if l := len(ops); l > 0 && ops[l-1].kind == kind {
+			ops[l-1].text += text
+			return
+		}
+		ops = append(ops, diffOp{kind, text})
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			push("equal", a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			push("removed", a[i])
+			i++
+		default:
+			push("added", b[j])
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		push("removed", a[i])
+	}
+	for ; j < m; j++ {
+		push("added", b[j])
+	}
+	return ops
+}
+
+// levenshteinRatio returns 1 minus the normalized Levenshtein edit distance
+// between a and b, so identical strings score 1 and completely dissimilar
+// ones score close to 0.
+func levenshteinRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	la, lb := len(ra), len(rb)
+	if la == 0 && lb == 0 {
+		return 1
+	}
+
+	prev := make([]int, lb+1)
+	cur := make([]int, lb+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= la; i++ {
+		cur[0] = i
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			min := prev[j] + 1 // deletion
+			if v := cur[j-1] + 1; v < min {
+				min = v // insertion
+			}
+			if v := prev[j-1] + cost; v < min {
+				min = v // substitution
+			}
+			cur[j] = min
+		}
+		prev, cur = cur, prev
+	}
+
+	dist := prev[lb]
+	maxLen := la
+	if lb > maxLen {
+		maxLen = lb
+	}
+	return 1 - float64(dist)/float64(maxLen)
+}
+
+// renderWordDiffPair writes a removed/added line pair, highlighting the
+// words that actually changed between them instead of coloring the whole
+// line. mode is "color" (bright/inverse ANSI on changed runs) or "plain"
+// ([-removed-]/{+added+} markers, no extra color). Pairs too dissimilar to
+// usefully align fall back to whole-line coloring.
+func renderWordDiffPair(w io.Writer, removed, added, mode, removedColor, addedColor string) {
+	if levenshteinRatio(removed, added) < wordDiffSimilarityThreshold {
+		writePlain(w, removedColor, "-"+removed)
+		writePlain(w, addedColor, "+"+added)
+		return
+	}
+
+	ops := diffTokens(tokenizeWords(removed), tokenizeWords(added))
+	writeWordDiffSide(w, "-", removedColor, "\x1b[1;41m", "removed", ops, mode)
+	writeWordDiffSide(w, "+", addedColor, "\x1b[1;42m", "added", ops, mode)
+}
+
+func writeWordDiffSide(w io.Writer, sign, color, changedColor, side string, ops []diffOp, mode string) {
+	io.WriteString(w, color)
+	io.WriteString(w, sign)
+	for _, op := range ops {
+		switch {
+		case op.kind == "equal":
+			io.WriteString(w, op.text)
+		case op.kind == side && mode == "plain":
+			if side == "removed" {
+				fmt.Fprintf(w, "[-%s-]", op.text)
+			} else {
+				fmt.Fprintf(w, "{+%s+}", op.text)
+			}
+		case op.kind == side:
+			fmt.Fprintf(w, "%s%s%s", changedColor, op.text, color)
+		}
+	}
+	io.WriteString(w, "\x1b[m\n")
+}
@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshteinRatio(t *testing.T) {
+	assert.Equal(t, float64(1), levenshteinRatio("same", "same"))
+	assert.Equal(t, float64(1), levenshteinRatio("", ""))
+	assert.InDelta(t, 0.8, levenshteinRatio("hello", "jello"), 0.01)
+	assert.Less(t, levenshteinRatio("abcdef", "zzzzzz"), 0.2)
+}
+
+func TestDiffTokens(t *testing.T) {
+	ops := diffTokens(tokenizeWords("hello, world"), tokenizeWords("hello, there"))
+	var kinds []string
+	for _, op := range ops {
+		kinds = append(kinds, op.kind)
+	}
+	assert.Equal(t, []string{"equal", "removed", "added"}, kinds)
+	assert.Equal(t, "hello, ", ops[0].text)
+	assert.Equal(t, "world", ops[1].text)
+	assert.Equal(t, "there", ops[2].text)
+}
+
+func TestRenderWordDiffPair_ColorMode(t *testing.T) {
+	var buf bytes.Buffer
+	renderWordDiffPair(&buf, "fmt.Println(\"hello\")", "fmt.Println(\"hello, world\")", "color", "\x1b[31m", "\x1b[32m")
+	out := buf.String()
+	assert.Contains(t, out, "\x1b[31m-")
+	assert.Contains(t, out, "\x1b[32m+")
+	assert.Contains(t, out, "\x1b[1;42m") // bright green for the added run
+	assert.Contains(t, out, "fmt.Println(\"hello")
+}
+
+func TestRenderWordDiffPair_PlainMode(t *testing.T) {
+	var buf bytes.Buffer
+	renderWordDiffPair(&buf, "fmt.Println(\"hello\")", "fmt.Println(\"hello, world\")", "plain", "\x1b[31m", "\x1b[32m")
+	out := buf.String()
+	assert.Contains(t, out, "{+, world+}")
+	assert.NotContains(t, out, "\x1b[1;42m")
+}
+
+func TestRenderWordDiffPair_CommonPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	renderWordDiffPair(&buf, "foo bar baz", "foo bar qux", "color", "\x1b[31m", "\x1b[32m")
+	out := buf.String()
+	assert.Contains(t, out, "\x1b[31m-foo bar ")
+	assert.Contains(t, out, "\x1b[1;41mbaz\x1b[31m")
+	assert.Contains(t, out, "\x1b[32m+foo bar ")
+	assert.Contains(t, out, "\x1b[1;42mqux\x1b[32m")
+}
+
+func TestRenderWordDiffPair_FallsBackWhenDissimilar(t *testing.T) {
+	var buf bytes.Buffer
+	renderWordDiffPair(&buf, "totally different content here", "completely unrelated other text", "color", "\x1b[31m", "\x1b[32m")
+	out := buf.String()
+	assert.Contains(t, out, "-totally different content here")
+	assert.Contains(t, out, "+completely unrelated other text")
+	assert.NotContains(t, out, "\x1b[1;41m")
+	assert.NotContains(t, out, "\x1b[1;42m")
+}
+
+func TestRenderDiff_WordDiff_MultiLineHunk(t *testing.T) {
+	patch := `diff --git a/greet.go b/greet.go
+index 83db48f..bf269f4 100644
+--- a/greet.go
++++ b/greet.go
+@@ -1,5 +1,5 @@
+ package main
+
+-func hello() { fmt.Println("hi") }
+-func bye() { fmt.Println("bye") }
++func hello() { fmt.Println("hi there") }
++func bye() { fmt.Println("goodbye") }
+`
+	var buf bytes.Buffer
+	err := renderDiff(bytes.NewReader([]byte(patch)), &buf, renderOpts{wordDiff: "color"})
+	assert.NoError(t, err)
+	out := buf.String()
+	assert.Contains(t, out, "\x1b[1;42m")
+	assert.Contains(t, out, "\x1b[1;41m")
+}
+
+func TestRenderDiff_WordDiff_UnequalCounts(t *testing.T) {
+	patch := `diff --git a/greet.go b/greet.go
+index 83db48f..bf269f4 100644
+--- a/greet.go
++++ b/greet.go
+@@ -1,3 +1,2 @@
+ package main
+-func a() {}
+-func b() {}
++func x() {}
+`
+	var buf bytes.Buffer
+	err := renderDiff(bytes.NewReader([]byte(patch)), &buf, renderOpts{wordDiff: "color"})
+	assert.NoError(t, err)
+	out := buf.String()
+	removedAt := strings.Index(out, "-func a() {}")
+	removedBAt := strings.Index(out, "-func b() {}")
+	addedAt := strings.Index(out, "+func x() {}")
+	assert.True(t, removedAt >= 0 && removedBAt >= 0 && addedAt >= 0)
+	assert.True(t, removedAt < removedBAt, "removed lines should stay in original order")
+	assert.True(t, removedBAt < addedAt, "removed lines should be rendered before added lines when counts differ")
+}
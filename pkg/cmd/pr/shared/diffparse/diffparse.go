@@ -0,0 +1,136 @@
+// Package diffparse provides a streaming parser for unified diffs as
+// produced by the GitHub pull request diff API, turning the raw text into
+// per-file summaries (path, rename, additions/deletions, status) without
+// ever holding the whole diff in memory at once.
+package diffparse
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Status mirrors the file-level status vocabulary used elsewhere in the API
+// (e.g. `gh pr view --json files`).
+const (
+	StatusAdded    = "added"
+	StatusRemoved  = "removed"
+	StatusModified = "modified"
+	StatusRenamed  = "renamed"
+)
+
+// FileDiff summarizes the changes made to a single file within a diff.
+type FileDiff struct {
+	Path         string `json:"path"`
+	PreviousPath string `json:"previous_path,omitempty"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	Status       string `json:"status"`
+	Patch        string `json:"patch,omitempty"`
+	Binary       bool   `json:"-"`
+}
+
+var (
+	diffGitPrefix    = "diff --git a/"
+	renameFromPrefix = "rename from "
+	renameToPrefix   = "rename to "
+	newFileMarker    = "new file mode "
+	deletedMarker    = "deleted file mode "
+	binaryMarker     = "Binary files "
+)
+
+// Each streams r line by line and invokes fn once per file encountered in
+// the diff, in order. When includePatch is true, the full per-file diff
+// text (starting with its `diff --git` header) is collected into
+// FileDiff.Patch; otherwise only the additions/deletions counts are
+// tallied and the hunk bodies are discarded as they're read.
+func Each(r io.Reader, includePatch bool, fn func(FileDiff) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	var cur *FileDiff
+	var patch strings.Builder
+
+	flush := func() error {
+		if cur == nil {
+			return nil
+		}
+		if cur.Status == "" {
+			cur.Status = StatusModified
+		}
+		if includePatch {
+			cur.Patch = patch.String()
+		}
+		err := fn(*cur)
+		cur = nil
+		patch.Reset()
+		return err
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, diffGitPrefix) {
+			if err := flush(); err != nil {
+				return err
+			}
+			rest := strings.TrimPrefix(line, diffGitPrefix)
+			_, b, ok := splitGitPaths(rest)
+			if !ok {
+				continue
+			}
+			cur = &FileDiff{Path: b}
+			if includePatch {
+				patch.WriteString(line)
+				patch.WriteByte('\n')
+			}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, newFileMarker):
+			cur.Status = StatusAdded
+		case strings.HasPrefix(line, deletedMarker):
+			cur.Status = StatusRemoved
+			cur.PreviousPath = ""
+		case strings.HasPrefix(line, renameFromPrefix):
+			cur.Status = StatusRenamed
+			cur.PreviousPath = strings.TrimPrefix(line, renameFromPrefix)
+		case strings.HasPrefix(line, renameToPrefix):
+			cur.Status = StatusRenamed
+			cur.Path = strings.TrimPrefix(line, renameToPrefix)
+		case strings.HasPrefix(line, binaryMarker):
+			cur.Binary = true
+		case strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "--- "):
+			// part of the hunk header, not a content line
+		case strings.HasPrefix(line, "+"):
+			cur.Additions++
+		case strings.HasPrefix(line, "-"):
+			cur.Deletions++
+		}
+
+		if includePatch {
+			patch.WriteString(line)
+			patch.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// splitGitPaths splits the "a/path b/path" remainder of a `diff --git`
+// header. Paths containing spaces make this ambiguous in general, but
+// GitHub's diff API quotes such paths, which we don't attempt to unquote
+// here; the common case of unquoted paths splits unambiguously on " b/".
+func splitGitPaths(s string) (a, b string, ok bool) {
+	idx := strings.Index(s, " b/")
+	if idx < 0 {
+		return "", "", false
+	}
+	return s[:idx], s[idx+len(" b/"):], true
+}
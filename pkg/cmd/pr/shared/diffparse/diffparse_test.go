@@ -0,0 +1,83 @@
+package diffparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEach(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 83db48f..bf269f4 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-func old() {}
++func new() {}
+diff --git a/old_name.go b/new_name.go
+similarity index 90%
+rename from old_name.go
+rename to new_name.go
+index 1234567..89abcde 100644
+--- a/old_name.go
++++ b/new_name.go
+@@ -1,2 +1,3 @@
+ package main
++// added a comment
+diff --git a/removed.go b/removed.go
+deleted file mode 100644
+index abcdef0..0000000
+--- a/removed.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package main
+-func gone() {}
+diff --git a/image.png b/image.png
+new file mode 100644
+index 0000000..abcdef0
+Binary files /dev/null and b/image.png differ
+`
+
+	var files []FileDiff
+	err := Each(strings.NewReader(diff), true, func(f FileDiff) error {
+		files = append(files, f)
+		return nil
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, files, 4) {
+		return
+	}
+
+	assert.Equal(t, "main.go", files[0].Path)
+	assert.Equal(t, StatusModified, files[0].Status)
+	assert.Equal(t, 1, files[0].Additions)
+	assert.Equal(t, 1, files[0].Deletions)
+	assert.Contains(t, files[0].Patch, "diff --git a/main.go b/main.go")
+
+	assert.Equal(t, "new_name.go", files[1].Path)
+	assert.Equal(t, "old_name.go", files[1].PreviousPath)
+	assert.Equal(t, StatusRenamed, files[1].Status)
+	assert.Equal(t, 1, files[1].Additions)
+
+	assert.Equal(t, "removed.go", files[2].Path)
+	assert.Equal(t, StatusRemoved, files[2].Status)
+	assert.Equal(t, 2, files[2].Deletions)
+
+	assert.Equal(t, "image.png", files[3].Path)
+	assert.Equal(t, StatusAdded, files[3].Status)
+	assert.True(t, files[3].Binary)
+	assert.Contains(t, files[3].Patch, "diff --git a/image.png b/image.png")
+	assert.Contains(t, files[3].Patch, "Binary files /dev/null and b/image.png differ")
+}
+
+func TestEach_EmptyDiff(t *testing.T) {
+	var files []FileDiff
+	err := Each(strings.NewReader(""), false, func(f FileDiff) error {
+		files = append(files, f)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}